@@ -0,0 +1,108 @@
+package main
+
+import "fmt"
+
+// Severity classifies how serious a Finding is. Only error-severity
+// findings affect the process exit code.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single validation result, carrying enough structured data
+// for every Reporter (text, json, sarif, github) to render it consistently.
+type Finding struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	RuleID   string   `json:"ruleID"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Error renders a Finding the way the tool has always printed one, so the
+// text Reporter's output matches the original fmt.Println behavior.
+func (f Finding) Error() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d %s", f.File, f.Line, f.Message)
+	}
+	return f.Message
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// Every constructor below takes file explicitly rather than reading shared
+// state, so a single process can validate several documents - from several
+// files, or several concurrent HTTP requests - without findings from one
+// bleeding into another.
+
+func NewTypeError(file, key, mustBe, ruleID string, line, column int) Finding {
+	return Finding{
+		File:     file,
+		Line:     line,
+		Column:   column,
+		RuleID:   orDefault(ruleID, "type-mismatch"),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("%s must be %s", key, mustBe),
+	}
+}
+
+func NewRequiredFieldError(file, key, ruleID string) Finding {
+	return Finding{
+		File:     file,
+		RuleID:   orDefault(ruleID, "required-field-missing"),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("%s is required", key),
+	}
+}
+
+func NewRequiredFieldErrorWithLine(file, key, ruleID string, line, column int) Finding {
+	return Finding{
+		File:     file,
+		Line:     line,
+		Column:   column,
+		RuleID:   orDefault(ruleID, "required-field-missing"),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("%s is required", key),
+	}
+}
+
+func NewOutOfRangeError(file, key, ruleID string, line, column int) Finding {
+	return Finding{
+		File:     file,
+		Line:     line,
+		Column:   column,
+		RuleID:   orDefault(ruleID, "value-out-of-range"),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("%s value out of range", key),
+	}
+}
+
+func NewInvalidFormatError(file, key, value, ruleID string, line, column int) Finding {
+	return Finding{
+		File:     file,
+		Line:     line,
+		Column:   column,
+		RuleID:   orDefault(ruleID, "invalid-format"),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("%s has invalid format '%s'", key, value),
+	}
+}
+
+func NewUnsupportedValueError(file, key, value, ruleID string, line, column int) Finding {
+	return Finding{
+		File:     file,
+		Line:     line,
+		Column:   column,
+		RuleID:   orDefault(ruleID, "unsupported-value"),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("%s has unsupported value '%s'", key, value),
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// resolveInputs expands the CLI arguments - files, directories and glob
+// patterns - into a flat, deduplicated list of .yaml/.yml file paths.
+// Directories are scanned non-recursively unless recursive is set.
+func resolveInputs(args []string, recursive bool) ([]string, error) {
+	seen := make(map[string]bool)
+	files := make([]string, 0, len(args))
+
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			matches = []string{arg}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("%s does not exist", match)
+			}
+
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+
+			dirFiles, err := collectYAMLFiles(match, recursive)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range dirFiles {
+				add(f)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+func collectYAMLFiles(dir string, recursive bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read directory %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+
+		if entry.IsDir() {
+			if recursive {
+				nested, err := collectYAMLFiles(path, recursive)
+				if err != nil {
+					return nil, err
+				}
+				files = append(files, nested...)
+			}
+			continue
+		}
+
+		if isYAMLFile(path) {
+			files = append(files, path)
+		}
+	}
+
+	return files, nil
+}
+
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// decodeDocuments splits a YAML byte stream into its "---"-separated
+// documents, unwrapping each one from its enclosing DocumentNode.
+func decodeDocuments(data []byte) ([]*yaml.Node, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	docs := make([]*yaml.Node, 0, 1)
+
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return docs, err
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		docs = append(docs, doc.Content[0])
+	}
+
+	return docs, nil
+}
@@ -1,383 +1,172 @@
 package main
 
 import (
-	"errors"
+	"flag"
 	"fmt"
-	"gopkg.in/yaml.v3"
 	"os"
-	"path/filepath"
-	"regexp"
-	"strconv"
-	"strings"
-)
-
-var (
-	absPath string
-	relPath string
-)
-
-type PodOS string
 
-const (
-	Linux   PodOS = "linux"
-	Windows PodOS = "windows"
+	"gopkg.in/yaml.v3"
 )
 
-type Protocol string
+var validators = DefaultRegistry()
 
-const (
-	TCP Protocol = "TCP"
-	UDP Protocol = "UDP"
-)
+var policyRules *PolicyEngine
 
-func init() {
-	if len(os.Args[1:]) != 1 {
-		panic("path to yaml is not provided")
-	}
-	filePath := os.Args[1]
-	_, err := os.Stat(filePath)
-	if errors.Is(err, os.ErrNotExist) {
-		panic(fmt.Sprintf("%s does not exist", filePath))
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runServe(os.Args[2:]); err != nil {
+			panic(err)
+		}
+		return
 	}
-	absPath, _ = filepath.Abs(filePath)
-	parentDir := filepath.Dir(filePath)
-	relPath, _ = filepath.Rel(parentDir, filePath)
+	runValidate(os.Args[1:])
 }
 
-func main() {
-	var root yaml.Node
-	data, _ := os.ReadFile(absPath)
-	err := yaml.Unmarshal(data, &root)
-
-	if err != nil {
-		panic(fmt.Errorf("cannot unmarshal file content: %w", err))
+// runValidate is the original CLI entry point: validate the given files
+// (or directories/globs) once, report the findings, and optionally watch
+// for changes.
+func runValidate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	recursive := fs.Bool("r", false, "recurse into directories given on the command line")
+	format := fs.String("format", "text", "output format: text, json, sarif, github")
+	watchMode := fs.Bool("watch", false, "re-run validation when an input file changes")
+	rulesPath := fs.String("rules", "", "path to a CEL policy file with org-specific rules")
+	if err := fs.Parse(args); err != nil {
+		panic(err)
 	}
 
-	errs := validateManifesto(&root)
-
-	for _, err := range errs {
-		fmt.Println(err)
+	inputArgs := fs.Args()
+	if len(inputArgs) == 0 {
+		panic("path to yaml is not provided")
 	}
-}
 
-func validateManifesto(root *yaml.Node) []error {
-	errs := make([]error, 0)
-	for _, doc := range root.Content {
-		traverseCore(doc, &errs)
+	inputFiles, err := resolveInputs(inputArgs, *recursive)
+	if err != nil {
+		panic(err)
 	}
-	return errs
-}
-
-func checkRequiredFields(visited map[string]bool, required []string, errs *[]error) {
-	for _, field := range required {
-		if !visited[field] {
-			*errs = append(*errs, NewRequiredFieldError(field))
-		}
+	if len(inputFiles) == 0 {
+		panic("no .yaml/.yml files found in the given input")
 	}
-}
 
-func NewTypeError(key, mustBe string, line int) error {
-	return fmt.Errorf("%s:%d %s must be %s", relPath, line, key, mustBe)
-}
-
-func NewRequiredFieldError(key string) error {
-	return fmt.Errorf("%s is required", key)
-}
-
-func NewRequiredFieldErrorWithLine(key string, line int) error {
-	return fmt.Errorf("%s:%d %s is required", relPath, line, key)
-}
-
-func NewOutOfRangeError(key string, line int) error {
-	return fmt.Errorf("%s:%d %s value out of range", relPath, line, key)
-}
-
-func NewInvalidFormatError(key, value string, line int) error {
-	return fmt.Errorf("%s:%d %s has invalid format '%s'", absPath, line, key, value)
-}
-
-func NewUnsupportedValueError(key, value string, line int) error {
-	return fmt.Errorf("%s:%d %s has unsupported value '%s'", relPath, line, key, value)
-}
-
-func traverseCore(doc *yaml.Node, errs *[]error) {
-	visited := make(map[string]bool)
-	required := []string{"apiVersion", "kind", "metadata", "spec"}
-	defer checkRequiredFields(visited, required, errs)
-
-	for i := 0; i < len(doc.Content); i += 2 {
-		key := doc.Content[i]
-		value := doc.Content[i+1]
-
-		switch key.Value {
-		case "apiVersion":
-			if value.Value != "v1" {
-				*errs = append(*errs, NewUnsupportedValueError(key.Value, value.Value, key.Line))
-			}
-			visited["apiVersion"] = true
-		case "kind":
-			if value.Value != "Pod" {
-				*errs = append(*errs, NewUnsupportedValueError(key.Value, value.Value, key.Line))
-			}
-			visited["kind"] = true
-		case "metadata":
-			traverseMetadata(value, errs)
-			visited["metadata"] = true
-		case "spec":
-			traverseSpec(value, errs)
-			visited["spec"] = true
+	if *rulesPath != "" {
+		policy, err := LoadPolicyFile(*rulesPath)
+		if err != nil {
+			panic(err)
 		}
+		policyRules = NewPolicyEngine(policy)
 	}
 
-}
-
-func traverseMetadata(node *yaml.Node, errs *[]error) {
-	visited := make(map[string]bool)
-	required := []string{"name"}
-	defer checkRequiredFields(visited, required, errs)
-
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
+	reporter, err := NewReporter(*format)
+	if err != nil {
+		panic(err)
+	}
 
-		switch key.Value {
-		case "name":
-			if value.Value == "" {
-				*errs = append(*errs, NewRequiredFieldErrorWithLine(key.Value, key.Line))
-			}
-			visited["name"] = true
-		case "namespace":
-			visited["namespace"] = true
-		case "labels":
-			traverseLabels(value, errs)
-			visited["labels"] = true
-		}
+	results, err := validateManifesto(inputFiles)
+	if err != nil {
+		panic(err)
 	}
-}
 
-func traverseLabels(node *yaml.Node, errs *[]error) {
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
+	if err := reporter.Report(os.Stdout, results); err != nil {
+		panic(err)
+	}
 
-		if value.Kind != yaml.ScalarNode {
-			*errs = append(*errs, NewTypeError(key.Value, "string", key.Line))
-			continue
+	if *watchMode {
+		if err := watch(inputArgs, *recursive, reporter); err != nil {
+			panic(err)
 		}
+		return
 	}
-}
-
-func traverseSpec(node *yaml.Node, errs *[]error) {
-	visited := make(map[string]bool)
-	required := []string{"containers"}
-	defer checkRequiredFields(visited, required, errs)
-
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
 
-		switch key.Value {
-		case "os":
-			if PodOS(value.Value) != Linux && PodOS(value.Value) != Windows {
-				*errs = append(*errs, NewUnsupportedValueError(key.Value, value.Value, key.Line))
-			}
-			visited["os"] = true
-		case "containers":
-			for _, container := range value.Content {
-				traverseContainer(container, errs)
-			}
-			visited["containers"] = true
-		}
+	if hasErrorSeverity(results) {
+		os.Exit(1)
 	}
 }
 
-func traverseContainer(node *yaml.Node, errs *[]error) {
-	visited := make(map[string]bool)
-	required := []string{"name", "image", "resources"}
-	defer checkRequiredFields(visited, required, errs)
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
-
-		switch key.Value {
-		case "name":
-			if value.Value == "" {
-				*errs = append(*errs, NewRequiredFieldErrorWithLine(key.Value, key.Line))
-				visited["name"] = true
-				continue
-			}
-			if value.Value != ToSnakeCase(value.Value) {
-				*errs = append(*errs, NewInvalidFormatError(key.Value, value.Value, key.Line))
+func hasErrorSeverity(results []DocumentResult) bool {
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			if finding.Severity == SeverityError {
+				return true
 			}
-			visited["name"] = true
-		case "image":
-			pattern := regexp.MustCompile(`^registry.bigbrother.io/(.*):(.*)$`)
-			if !pattern.MatchString(value.Value) {
-				*errs = append(*errs, NewInvalidFormatError(key.Value, value.Value, key.Line))
-			}
-			visited["image"] = true
-		case "ports":
-			for _, port := range value.Content {
-				traverseContainerPort(port, errs)
-			}
-			visited["ports"] = true
-		case "readinessProbe":
-			traverseProbe(value, errs)
-			visited["readinessProbe"] = true
-		case "livenessProbe":
-			traverseProbe(value, errs)
-			visited["livenessProbe"] = true
-		case "resources":
-			traverseResources(value, errs)
-			visited["resources"] = true
 		}
 	}
+	return false
 }
 
-func traverseContainerPort(node *yaml.Node, errs *[]error) {
-	required := []string{"containerPort"}
-	visited := make(map[string]bool)
-	defer checkRequiredFields(visited, required, errs)
-
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
-
-		switch key.Value {
-		case "containerPort":
-			if value.Tag != "!!int" {
-				*errs = append(*errs, NewTypeError(key.Value, "int", key.Line))
-				visited["containerPort"] = true
-				continue
-			}
-			number, _ := strconv.Atoi(value.Value)
-			if number < 0 || number > 65535 {
-				*errs = append(*errs, NewOutOfRangeError(key.Value, key.Line))
-			}
-			visited["containerPort"] = true
-		case "protocol":
-			if Protocol(value.Value) != TCP && Protocol(value.Value) != UDP {
-				*errs = append(*errs, NewUnsupportedValueError(key.Value, value.Value, key.Line))
-			}
-			visited["protocol"] = true
-		}
-	}
+// DocumentKey identifies a single manifest document among the inputs being
+// validated - a file may contain several "---"-separated documents.
+type DocumentKey struct {
+	File     string
+	DocIndex int
+	Kind     string
+	Name     string
 }
 
-func traverseProbe(node *yaml.Node, errs *[]error) {
-	visited := make(map[string]bool)
-	required := []string{"httpGet"}
-	defer checkRequiredFields(visited, required, errs)
+// DocumentResult groups the findings for one DocumentKey, so reporters can
+// present results per-document instead of as one flat error stream.
+type DocumentResult struct {
+	Key      DocumentKey
+	Findings []Finding
+}
 
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
+// validateManifesto validates every document in every file, in order,
+// grouping the findings per document.
+func validateManifesto(files []string) ([]DocumentResult, error) {
+	results := make([]DocumentResult, 0)
 
-		switch key.Value {
-		case "httpGet":
-			traverseHTTPGet(value, errs)
-			visited["httpGet"] = true
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return results, fmt.Errorf("cannot read %s: %w", file, err)
 		}
-	}
-}
-
-func traverseHTTPGet(node *yaml.Node, errs *[]error) {
-	visited := make(map[string]bool)
-	required := []string{"path", "port"}
-	defer checkRequiredFields(visited, required, errs)
 
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
+		docs, err := decodeDocuments(data)
+		if err != nil {
+			return results, fmt.Errorf("cannot unmarshal %s: %w", file, err)
+		}
 
-		switch key.Value {
-		case "path":
-			if !strings.HasPrefix(value.Value, "/") {
-				*errs = append(*errs, NewInvalidFormatError(key.Value, value.Value, key.Line))
-			}
-			visited["path"] = true
-		case "port":
-			if value.Tag != "!!int" {
-				*errs = append(*errs, NewTypeError(key.Value, "int", key.Line))
-				visited["port"] = true
-				continue
-			}
-			number, _ := strconv.Atoi(value.Value)
-			if number < 0 || number > 65535 {
-				*errs = append(*errs, NewOutOfRangeError(key.Value, key.Line))
-			}
-			visited["port"] = true
+		for i, doc := range docs {
+			results = append(results, validateDocument(file, i, doc))
 		}
 	}
+
+	return results, nil
 }
-func traverseResources(node *yaml.Node, errs *[]error) {
-	required := []string{}
-	visited := make(map[string]bool)
-	defer checkRequiredFields(visited, required, errs)
 
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
+func validateDocument(file string, docIndex int, doc *yaml.Node) DocumentResult {
+	findings := make([]Finding, 0)
+	key := DocumentKey{File: file, DocIndex: docIndex}
 
-		switch key.Value {
-		case "requests":
-			traverseResourceDeclaration(value, errs)
-			visited["requests"] = true
-		case "limits":
-			traverseResourceDeclaration(value, errs)
-			visited["limits"] = true
+	apiVersion := lookupChild(doc, "apiVersion")
+	kind := lookupChild(doc, "kind")
+	if kind != nil {
+		key.Kind = kind.Value
+	}
+	if metadata := lookupChild(doc, "metadata"); metadata != nil {
+		if name := lookupChild(metadata, "name"); name != nil {
+			key.Name = name.Value
 		}
 	}
-}
-
-func traverseResourceDeclaration(node *yaml.Node, errs *[]error) {
-	required := []string{}
-	visited := make(map[string]bool)
-	defer checkRequiredFields(visited, required, errs)
-
-	for i := 0; i < len(node.Content); i += 2 {
-		key := node.Content[i]
-		value := node.Content[i+1]
 
-		switch key.Value {
-		case "cpu":
-			if value.Tag != "!!int" {
-				*errs = append(*errs, NewTypeError(key.Value, "int", key.Line))
-				visited["cpu"] = true
-				continue
-			}
-			number, _ := strconv.Atoi(value.Value)
-			if number < 1 {
-				*errs = append(*errs, NewOutOfRangeError(key.Value, key.Line))
-			}
-			visited["cpu"] = true
-		case "memory":
-			pattern := regexp.MustCompile(`^(\d+)(Mi|Gi|Ki)$`)
-			result := pattern.FindStringSubmatch(value.Value)
-			if len(result) != 3 {
-				*errs = append(*errs, NewInvalidFormatError(key.Value, value.Value, key.Line))
-				visited["memory"] = true
-				continue
-			}
-			amount, err := strconv.Atoi(result[1])
-			if err != nil {
-				*errs = append(*errs, NewTypeError(key.Value, "int", key.Line))
-				visited["memory"] = true
-				continue
-			}
-			if amount < 1 {
-				*errs = append(*errs, NewOutOfRangeError(key.Value, key.Line))
-			}
-			visited["memory"] = true
-		}
+	if apiVersion == nil {
+		findings = append(findings, NewRequiredFieldError(file, "apiVersion", ""))
+	}
+	if kind == nil {
+		findings = append(findings, NewRequiredFieldError(file, "kind", ""))
+	}
+	if apiVersion == nil || kind == nil {
+		return DocumentResult{Key: key, Findings: findings}
 	}
-}
 
-var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
-var matchAllCap = regexp.MustCompile("([a-z0-9])([A-Z])")
+	validator, ok := validators.Lookup(apiVersion.Value, kind.Value)
+	if !ok {
+		findings = append(findings, NewUnsupportedValueError(file, "kind", kind.Value, "unsupported-kind", kind.Line, kind.Column))
+		return DocumentResult{Key: key, Findings: findings}
+	}
 
-func ToSnakeCase(str string) string {
-	snake := matchFirstCap.ReplaceAllString(str, "${1}_${2}")
-	snake = matchAllCap.ReplaceAllString(snake, "${1}_${2}")
-	return strings.ToLower(snake)
+	findings = append(findings, validator.Validate(doc, file)...)
+	if policyRules != nil {
+		findings = append(findings, policyRules.Evaluate(doc, file)...)
+	}
+	return DocumentResult{Key: key, Findings: findings}
 }
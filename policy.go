@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyRule is one org-specific check: Selector picks the nodes it applies
+// to (JSONPath-like, e.g. "spec.containers[*]"), and Expression is a CEL
+// predicate evaluated with the selected node bound to a "self" variable, e.g.
+// "has(self.securityContext.runAsNonRoot) && self.securityContext.runAsNonRoot".
+// Expression must evaluate to a bool; false means the rule is violated.
+type PolicyRule struct {
+	Selector   string   `yaml:"selector"`
+	Expression string   `yaml:"expression"`
+	Message    string   `yaml:"message"`
+	Severity   Severity `yaml:"severity"`
+	RuleID     string   `yaml:"ruleID"`
+}
+
+// PolicyFile is the top-level shape of a --rules policy document.
+type PolicyFile struct {
+	Rules []PolicyRule `yaml:"rules"`
+}
+
+// LoadPolicyFile reads and decodes a PolicyFile from path.
+func LoadPolicyFile(path string) (PolicyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PolicyFile{}, fmt.Errorf("cannot read policy file %s: %w", path, err)
+	}
+
+	var policy PolicyFile
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return PolicyFile{}, fmt.Errorf("cannot unmarshal policy file %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// PolicyEngine evaluates a PolicyFile's rules against a document, on top of
+// whatever the document's Kind-specific Validator already checks.
+type PolicyEngine struct {
+	rules []PolicyRule
+}
+
+func NewPolicyEngine(policy PolicyFile) *PolicyEngine {
+	return &PolicyEngine{rules: policy.Rules}
+}
+
+func (e *PolicyEngine) Evaluate(doc *yaml.Node, file string) []Finding {
+	findings := make([]Finding, 0)
+	for _, rule := range e.rules {
+		for _, node := range resolveNodes(doc, parseSelector(rule.Selector)) {
+			findings = append(findings, evaluatePolicyRule(file, rule, node)...)
+		}
+	}
+	return findings
+}
+
+// parseSelector turns a JSONPath-like selector such as "spec.containers[*]"
+// into the dot/"[]" segment form resolveNodes already understands.
+func parseSelector(selector string) []string {
+	selector = strings.ReplaceAll(selector, "[*]", ".[]")
+	raw := strings.Split(selector, ".")
+	segments := make([]string, 0, len(raw))
+	for _, segment := range raw {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+func evaluatePolicyRule(file string, rule PolicyRule, node *yaml.Node) []Finding {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return []Finding{policyErrorFinding(file, rule, node, err)}
+	}
+
+	fields, _ := value.(map[string]interface{})
+	passed, err := evaluateCEL(rule.Expression, fields)
+	if err != nil {
+		return []Finding{policyErrorFinding(file, rule, node, err)}
+	}
+	if passed {
+		return nil
+	}
+
+	return []Finding{{
+		File:     file,
+		Line:     node.Line,
+		Column:   node.Column,
+		RuleID:   orDefault(rule.RuleID, "policy-violation"),
+		Severity: orDefaultSeverity(rule.Severity),
+		Message:  rule.Message,
+	}}
+}
+
+func policyErrorFinding(file string, rule PolicyRule, node *yaml.Node, err error) Finding {
+	return Finding{
+		File:     file,
+		Line:     node.Line,
+		Column:   node.Column,
+		RuleID:   orDefault(rule.RuleID, "policy-error"),
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("policy %q failed to evaluate: %v", rule.Expression, err),
+	}
+}
+
+func orDefaultSeverity(severity Severity) Severity {
+	if severity == "" {
+		return SeverityError
+	}
+	return severity
+}
+
+// celSelfVar is the single variable every policy expression is evaluated
+// against, bound to the whole matched node. A fixed variable - rather than
+// one declared per key actually present on a given match - keeps the CEL
+// environment the same across matches, so has(self.someOptionalField) works
+// whether or not someOptionalField happens to exist on this particular node.
+const celSelfVar = "self"
+
+// evaluateCEL compiles and runs expression with fields bound to celSelfVar.
+func evaluateCEL(expression string, fields map[string]interface{}) (bool, error) {
+	env, err := cel.NewEnv(cel.Variable(celSelfVar, cel.DynType))
+	if err != nil {
+		return false, fmt.Errorf("cannot build CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return false, fmt.Errorf("cannot compile expression: %w", issues.Err())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return false, fmt.Errorf("cannot build CEL program: %w", err)
+	}
+
+	out, _, err := program.Eval(map[string]interface{}{celSelfVar: fields})
+	if err != nil {
+		return false, fmt.Errorf("cannot evaluate expression: %w", err)
+	}
+
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression must evaluate to a bool, got %T", out.Value())
+	}
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import "testing"
+
+// TestEvaluateCELOptionalField pins the bug where a rule referencing a field
+// absent from the current match (e.g. a container with no securityContext)
+// failed to *compile* instead of letting has() treat it as absent.
+func TestEvaluateCELOptionalField(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		fields     map[string]interface{}
+		want       bool
+		wantErr    bool
+	}{
+		{
+			name:       "has() guards a field absent from this match",
+			expression: `!has(self.securityContext) || self.securityContext.runAsNonRoot == true`,
+			fields:     map[string]interface{}{"name": "app"},
+			want:       true,
+		},
+		{
+			name:       "has() guards a present field that passes the check",
+			expression: `!has(self.securityContext) || self.securityContext.runAsNonRoot == true`,
+			fields: map[string]interface{}{
+				"name":            "app",
+				"securityContext": map[string]interface{}{"runAsNonRoot": true},
+			},
+			want: true,
+		},
+		{
+			name:       "has() guards a present field that fails the check",
+			expression: `!has(self.securityContext) || self.securityContext.runAsNonRoot == true`,
+			fields: map[string]interface{}{
+				"name":            "app",
+				"securityContext": map[string]interface{}{"runAsNonRoot": false},
+			},
+			want: false,
+		},
+		{
+			name:       "has() over a wholly absent nested block, guarded at every level",
+			expression: `!has(self.resources) || !has(self.resources.limits) || !has(self.resources.limits.memory) || self.resources.limits.memory != self.resources.requests.memory`,
+			fields:     map[string]interface{}{"name": "app"},
+			want:       true,
+		},
+		{
+			name:       "a malformed expression is a compile error",
+			expression: `self.name ==`,
+			fields:     map[string]interface{}{"name": "app"},
+			wantErr:    true,
+		},
+		{
+			name:       "a non-bool result is an evaluation error",
+			expression: `self.name`,
+			fields:     map[string]interface{}{"name": "app"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluateCEL(tt.expression, tt.fields)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got result %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evaluateCEL: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPolicyEngineEvaluateOptionalField exercises the same bug end to end,
+// through PolicyEngine.Evaluate against containers where only some of them
+// have the optional field a rule checks.
+func TestPolicyEngineEvaluateOptionalField(t *testing.T) {
+	doc := mustParseDoc(t, `
+spec:
+  containers:
+    - name: no-context
+    - name: compliant
+      securityContext:
+        runAsNonRoot: true
+    - name: non-compliant
+      securityContext:
+        runAsNonRoot: false
+`)
+
+	engine := NewPolicyEngine(PolicyFile{Rules: []PolicyRule{{
+		Selector:   "spec.containers[*]",
+		Expression: `!has(self.securityContext) || self.securityContext.runAsNonRoot == true`,
+		Message:    "containers must run as non-root",
+		RuleID:     "run-as-non-root",
+	}}})
+
+	findings := engine.Evaluate(doc, "pod.yaml")
+
+	if containsRuleID(findings, "policy-error") {
+		t.Fatalf("expected no policy-error findings from a container missing securityContext, got %v", findingRuleIDs(findings))
+	}
+	if !containsRuleID(findings, "run-as-non-root") {
+		t.Fatalf("expected a run-as-non-root finding for the non-compliant container, got %v", findingRuleIDs(findings))
+	}
+
+	violations := 0
+	for _, f := range findings {
+		if f.RuleID == "run-as-non-root" {
+			violations++
+		}
+	}
+	if violations != 1 {
+		t.Fatalf("expected exactly 1 run-as-non-root finding, got %d", violations)
+	}
+}
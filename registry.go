@@ -0,0 +1,56 @@
+package main
+
+import "gopkg.in/yaml.v3"
+
+// Validator validates a single decoded manifest document and returns any
+// errors found. file is carried through to every resulting Finding; it is
+// passed explicitly rather than read from shared state so a Validator is
+// safe to use from concurrent callers (e.g. the admission server validating
+// several requests at once). Implementations are free to walk the node tree
+// however they like; SchemaValidator drives this off a declarative Schema.
+type Validator interface {
+	Validate(node *yaml.Node, file string) []Finding
+}
+
+// GroupVersionKind identifies a Kubernetes resource type by the values of
+// its apiVersion and kind fields.
+type GroupVersionKind struct {
+	APIVersion string
+	Kind       string
+}
+
+// Registry maps a GroupVersionKind to the Validator responsible for it.
+// Callers register additional Kinds with Register; nothing here is specific
+// to Pod, so embedding tools can teach the linter about CRDs without
+// touching the traversal code.
+type Registry struct {
+	validators map[GroupVersionKind]Validator
+}
+
+func NewRegistry() *Registry {
+	return &Registry{validators: make(map[GroupVersionKind]Validator)}
+}
+
+// Register associates a Validator with a (apiVersion, kind) pair, replacing
+// any Validator previously registered for it.
+func (r *Registry) Register(gvk GroupVersionKind, validator Validator) {
+	r.validators[gvk] = validator
+}
+
+// Lookup returns the Validator registered for apiVersion/kind, if any.
+func (r *Registry) Lookup(apiVersion, kind string) (Validator, bool) {
+	validator, ok := r.validators[GroupVersionKind{APIVersion: apiVersion, Kind: kind}]
+	return validator, ok
+}
+
+// DefaultRegistry returns a Registry pre-populated with the Kinds this tool
+// understands out of the box.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(GroupVersionKind{APIVersion: "v1", Kind: "Pod"}, &SchemaValidator{schema: podSchema})
+	r.Register(GroupVersionKind{APIVersion: "apps/v1", Kind: "Deployment"}, &SchemaValidator{schema: deploymentSchema})
+	r.Register(GroupVersionKind{APIVersion: "v1", Kind: "Service"}, &SchemaValidator{schema: serviceSchema})
+	r.Register(GroupVersionKind{APIVersion: "v1", Kind: "ConfigMap"}, &SchemaValidator{schema: configMapSchema})
+	r.Register(GroupVersionKind{APIVersion: "v1", Kind: "Namespace"}, &SchemaValidator{schema: namespaceSchema})
+	return r
+}
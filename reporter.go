@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Reporter renders a set of DocumentResult for one output destination.
+type Reporter interface {
+	Report(w io.Writer, results []DocumentResult) error
+}
+
+// NewReporter returns the Reporter registered for format, defaulting to the
+// original line-by-line text output.
+func NewReporter(format string) (Reporter, error) {
+	switch format {
+	case "", "text":
+		return TextReporter{}, nil
+	case "json":
+		return JSONReporter{}, nil
+	case "sarif":
+		return SARIFReporter{}, nil
+	case "github":
+		return GitHubReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// TextReporter reproduces the tool's original output: one finding per line.
+type TextReporter struct{}
+
+func (TextReporter) Report(w io.Writer, results []DocumentResult) error {
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			if _, err := fmt.Fprintln(w, finding.Error()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// JSONReporter emits the findings, grouped per document, as JSON.
+type JSONReporter struct{}
+
+type jsonDocument struct {
+	File     string    `json:"file"`
+	DocIndex int       `json:"docIndex"`
+	Kind     string    `json:"kind,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Findings []Finding `json:"findings"`
+}
+
+func (JSONReporter) Report(w io.Writer, results []DocumentResult) error {
+	docs := make([]jsonDocument, 0, len(results))
+	for _, result := range results {
+		docs = append(docs, jsonDocument{
+			File:     result.Key.File,
+			DocIndex: result.Key.DocIndex,
+			Kind:     result.Key.Kind,
+			Name:     result.Key.Name,
+			Findings: result.Findings,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(docs)
+}
+
+// SARIFReporter emits findings as a minimal SARIF 2.1.0 log, suitable for
+// upload to GitHub code scanning.
+type SARIFReporter struct{}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func (SARIFReporter) Report(w io.Writer, results []DocumentResult) error {
+	run := sarifRun{Tool: sarifTool{Driver: sarifDriver{Name: "manifesto"}}}
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  finding.RuleID,
+				Level:   sarifLevel(finding.Severity),
+				Message: sarifMessage{Text: finding.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: finding.File},
+						Region:           sarifRegion{StartLine: finding.Line, StartColumn: finding.Column},
+					},
+				}},
+			})
+		}
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+func sarifLevel(severity Severity) string {
+	if severity == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// GitHubReporter emits GitHub Actions workflow command annotations
+// (`::error file=...,line=...::message`), one per finding.
+type GitHubReporter struct{}
+
+func (GitHubReporter) Report(w io.Writer, results []DocumentResult) error {
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			command := "error"
+			if finding.Severity == SeverityWarning {
+				command = "warning"
+			}
+			_, err := fmt.Fprintf(w, "::%s file=%s,line=%d::%s (%s)\n",
+				command, finding.File, finding.Line, finding.Message, finding.RuleID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
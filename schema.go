@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldRule describes the constraints applied to a single field of a
+// manifest. Path is a dot-separated walk from the document root, using
+// "[]" as a segment to iterate a sequence, e.g.
+// "spec.containers.[].resources.requests.memory".
+type FieldRule struct {
+	Path     string   `yaml:"path"`
+	Required bool     `yaml:"required"`
+	NotEmpty bool     `yaml:"notEmpty"`
+	Enum     []string `yaml:"enum"`
+	Pattern  string   `yaml:"pattern"`
+	MinInt   *int     `yaml:"minInt"`
+	MaxInt   *int     `yaml:"maxInt"`
+	// MinQuantity is checked against the first capture group of Pattern,
+	// e.g. the "512" in "512Mi". Used for memory-style quantities.
+	MinQuantity *int `yaml:"minQuantity"`
+	// AllValuesScalar requires every value of a mapping field to be a
+	// scalar, regardless of its key (e.g. metadata.labels).
+	AllValuesScalar bool `yaml:"allValuesScalar"`
+	// RuleID tags findings from this rule (e.g. "image-registry-mismatch").
+	// Defaults to a generic id naming the failed check when unset.
+	RuleID string `yaml:"ruleID"`
+	// Severity defaults to "error" when unset.
+	Severity Severity `yaml:"severity"`
+}
+
+func (r FieldRule) severity() Severity {
+	if r.Severity == "" {
+		return SeverityError
+	}
+	return r.Severity
+}
+
+// Schema is a compact, serializable descriptor of the rules for one Kind.
+// It is the external counterpart to a hard-coded traverse* function: adding
+// a Kind or tightening a rule means editing a Schema, not Go code.
+type Schema struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Rules      []FieldRule `yaml:"rules"`
+}
+
+// LoadSchema decodes a Schema from its YAML (or JSON, which is a subset of
+// YAML) representation.
+func LoadSchema(data []byte) (Schema, error) {
+	var schema Schema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return Schema{}, fmt.Errorf("cannot unmarshal schema: %w", err)
+	}
+	return schema, nil
+}
+
+// MustLoadSchema is LoadSchema for schemas baked into the binary, where a
+// decode failure is a programming error rather than user input.
+func MustLoadSchema(data []byte) Schema {
+	schema, err := LoadSchema(data)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// SchemaValidator is a Validator driven entirely by a Schema, with no
+// knowledge of any particular Kind.
+type SchemaValidator struct {
+	schema Schema
+}
+
+func NewSchemaValidator(schema Schema) *SchemaValidator {
+	return &SchemaValidator{schema: schema}
+}
+
+func (v *SchemaValidator) Validate(doc *yaml.Node, file string) []Finding {
+	findings := make([]Finding, 0)
+	for _, rule := range v.schema.Rules {
+		findings = append(findings, rule.validate(doc, file)...)
+	}
+	return findings
+}
+
+// validate resolves rule.Path against doc and checks every match. A rule's
+// path is split into the parent path and the leaf field name, so a missing
+// field is only reported when its parent is actually present - an absent
+// optional block (e.g. a Pod with no readinessProbe) must not trigger
+// "httpGet is required".
+func (r FieldRule) validate(doc *yaml.Node, file string) []Finding {
+	findings := make([]Finding, 0)
+	segments := strings.Split(r.Path, ".")
+	parentPath, leaf := segments[:len(segments)-1], segments[len(segments)-1]
+
+	for _, parent := range resolveNodes(doc, parentPath) {
+		child := lookupChild(parent, leaf)
+		if child == nil {
+			if r.Required {
+				findings = append(findings, NewRequiredFieldError(file, leaf, r.RuleID))
+			}
+			continue
+		}
+		findings = append(findings, r.check(file, leaf, child)...)
+	}
+
+	for i := range findings {
+		findings[i].Severity = r.severity()
+	}
+	return findings
+}
+
+func (r FieldRule) check(file, key string, value *yaml.Node) []Finding {
+	findings := make([]Finding, 0)
+
+	if r.NotEmpty && value.Value == "" {
+		return append(findings, NewRequiredFieldErrorWithLine(file, key, r.RuleID, value.Line, value.Column))
+	}
+
+	if r.AllValuesScalar {
+		for i := 0; i < len(value.Content); i += 2 {
+			if value.Content[i+1].Kind != yaml.ScalarNode {
+				findings = append(findings, NewTypeError(file, value.Content[i].Value, "string", r.RuleID, value.Content[i].Line, value.Content[i].Column))
+			}
+		}
+		return findings
+	}
+
+	if len(r.Enum) > 0 && !containsString(r.Enum, value.Value) {
+		findings = append(findings, NewUnsupportedValueError(file, key, value.Value, r.RuleID, value.Line, value.Column))
+	}
+
+	if r.Pattern != "" {
+		matches := regexp.MustCompile(r.Pattern).FindStringSubmatch(value.Value)
+		if matches == nil {
+			return append(findings, NewInvalidFormatError(file, key, value.Value, r.RuleID, value.Line, value.Column))
+		}
+		if r.MinQuantity != nil {
+			amount, err := strconv.Atoi(matches[1])
+			if err != nil || amount < *r.MinQuantity {
+				findings = append(findings, NewOutOfRangeError(file, key, r.RuleID, value.Line, value.Column))
+			}
+		}
+	}
+
+	if r.MinInt != nil || r.MaxInt != nil {
+		if value.Tag != "!!int" {
+			return append(findings, NewTypeError(file, key, "int", r.RuleID, value.Line, value.Column))
+		}
+		number, _ := strconv.Atoi(value.Value)
+		if (r.MinInt != nil && number < *r.MinInt) || (r.MaxInt != nil && number > *r.MaxInt) {
+			findings = append(findings, NewOutOfRangeError(file, key, r.RuleID, value.Line, value.Column))
+		}
+	}
+
+	return findings
+}
+
+// resolveNodes walks node along path, expanding a "[]" segment into every
+// element of the sequence at that point. It returns every node reached by
+// the end of path.
+func resolveNodes(node *yaml.Node, path []string) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if len(path) == 0 {
+		return []*yaml.Node{node}
+	}
+
+	segment, rest := path[0], path[1:]
+	if segment == "[]" {
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		var out []*yaml.Node
+		for _, item := range node.Content {
+			out = append(out, resolveNodes(item, rest)...)
+		}
+		return out
+	}
+
+	child := lookupChild(node, segment)
+	if child == nil {
+		return nil
+	}
+	return resolveNodes(child, rest)
+}
+
+// lookupChild returns the value node for key in a YAML mapping node, or nil
+// if node isn't a mapping or doesn't contain key.
+func lookupChild(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func mustParseDoc(t *testing.T, text string) *yaml.Node {
+	t.Helper()
+	docs, err := decodeDocuments([]byte(text))
+	if err != nil {
+		t.Fatalf("decodeDocuments: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("expected exactly one document, got %d", len(docs))
+	}
+	return docs[0]
+}
+
+func findingRuleIDs(findings []Finding) []string {
+	ids := make([]string, len(findings))
+	for i, f := range findings {
+		ids[i] = f.RuleID
+	}
+	return ids
+}
+
+func containsRuleID(findings []Finding, ruleID string) bool {
+	for _, id := range findingRuleIDs(findings) {
+		if id == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// TestSchemaValidatorRequiredFieldEdgeCases pins the "required field under
+// an absent optional parent is not reported" behavior that validate's
+// parent/leaf split exists to guarantee.
+func TestSchemaValidatorRequiredFieldEdgeCases(t *testing.T) {
+	base := `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: app
+      image: registry.bigbrother.io/app:v1
+      resources:
+        requests:
+          cpu: 1
+          memory: 512Mi
+        limits:
+          cpu: 2
+          memory: 1Gi
+      ports:
+        - containerPort: 8080
+`
+
+	t.Run("no readinessProbe at all is not reported", func(t *testing.T) {
+		doc := mustParseDoc(t, base)
+		findings := NewSchemaValidator(podSchema).Validate(doc, "pod.yaml")
+		if containsRuleID(findings, "required-field-missing") {
+			t.Errorf("expected no required-field-missing findings, got %v", findingRuleIDs(findings))
+		}
+	})
+
+	t.Run("readinessProbe present without httpGet is reported", func(t *testing.T) {
+		withProbe := base + `
+      readinessProbe:
+        initialDelaySeconds: 5
+`
+		doc := mustParseDoc(t, withProbe)
+		findings := NewSchemaValidator(podSchema).Validate(doc, "pod.yaml")
+		if !containsRuleID(findings, "required-field-missing") {
+			t.Errorf("expected a required-field-missing finding for httpGet, got %v", findingRuleIDs(findings))
+		}
+	})
+
+	t.Run("readinessProbe with httpGet but missing path is reported on that leaf", func(t *testing.T) {
+		withProbe := base + `
+      readinessProbe:
+        httpGet:
+          port: 8080
+`
+		doc := mustParseDoc(t, withProbe)
+		findings := NewSchemaValidator(podSchema).Validate(doc, "pod.yaml")
+		if !containsRuleID(findings, "required-field-missing") {
+			t.Errorf("expected a required-field-missing finding for path, got %v", findingRuleIDs(findings))
+		}
+	})
+}
+
+// TestFieldRuleCheckEnumPatternRange pins the per-constraint checks that
+// FieldRule.check performs, independent of path resolution.
+func TestFieldRuleCheckEnumPatternRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       FieldRule
+		value      string
+		wantRuleID string
+	}{
+		{
+			name:       "enum rejects value outside the set",
+			rule:       FieldRule{Enum: []string{"TCP", "UDP"}},
+			value:      "SCTP",
+			wantRuleID: "unsupported-value",
+		},
+		{
+			name:       "enum accepts a value in the set",
+			rule:       FieldRule{Enum: []string{"TCP", "UDP"}},
+			value:      "TCP",
+			wantRuleID: "",
+		},
+		{
+			name:       "pattern rejects a non-matching value",
+			rule:       FieldRule{Pattern: `^registry\.bigbrother\.io/(.*):(.*)$`},
+			value:      "docker.io/app:v1",
+			wantRuleID: "invalid-format",
+		},
+		{
+			name:       "quantity pattern rejects an amount below minQuantity",
+			rule:       FieldRule{Pattern: `^(\d+)(Mi|Gi|Ki)$`, MinQuantity: intPtr(256)},
+			value:      "128Mi",
+			wantRuleID: "value-out-of-range",
+		},
+		{
+			name:       "quantity pattern accepts an amount at minQuantity",
+			rule:       FieldRule{Pattern: `^(\d+)(Mi|Gi|Ki)$`, MinQuantity: intPtr(256)},
+			value:      "256Mi",
+			wantRuleID: "",
+		},
+		{
+			name:       "minInt/maxInt rejects a non-integer value",
+			rule:       FieldRule{MinInt: intPtr(0), MaxInt: intPtr(65535)},
+			value:      "not-a-port",
+			wantRuleID: "type-mismatch",
+		},
+		{
+			name:       "minInt/maxInt rejects a value above maxInt",
+			rule:       FieldRule{MinInt: intPtr(0), MaxInt: intPtr(65535)},
+			value:      "70000",
+			wantRuleID: "value-out-of-range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatalf("yaml.Unmarshal: %v", err)
+			}
+			node := value.Content[0]
+
+			findings := tt.rule.check("field.yaml", "field", node)
+			if tt.wantRuleID == "" {
+				if len(findings) != 0 {
+					t.Fatalf("expected no findings, got %v", findingRuleIDs(findings))
+				}
+				return
+			}
+			if !containsRuleID(findings, tt.wantRuleID) {
+				t.Fatalf("expected ruleID %q, got %v", tt.wantRuleID, findingRuleIDs(findings))
+			}
+		})
+	}
+}
+
+// TestResolveNodes pins the path-walking semantics resolveNodes provides to
+// every FieldRule and PolicyRule selector: plain field descent, "[]"
+// sequence expansion, and the "absent parent yields no nodes" case that
+// makes optional blocks safe to describe.
+func TestResolveNodes(t *testing.T) {
+	doc := mustParseDoc(t, `
+spec:
+  containers:
+    - name: a
+    - name: b
+`)
+
+	t.Run("descends through plain segments", func(t *testing.T) {
+		nodes := resolveNodes(doc, []string{"spec"})
+		if len(nodes) != 1 {
+			t.Fatalf("expected 1 node, got %d", len(nodes))
+		}
+	})
+
+	t.Run("expands a sequence segment into every element", func(t *testing.T) {
+		nodes := resolveNodes(doc, []string{"spec", "containers", "[]"})
+		if len(nodes) != 2 {
+			t.Fatalf("expected 2 nodes, got %d", len(nodes))
+		}
+	})
+
+	t.Run("a missing parent yields no nodes rather than an error", func(t *testing.T) {
+		nodes := resolveNodes(doc, []string{"spec", "volumes", "[]"})
+		if len(nodes) != 0 {
+			t.Fatalf("expected 0 nodes, got %d", len(nodes))
+		}
+	})
+}
+
+func intPtr(v int) *int { return &v }
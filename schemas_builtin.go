@@ -0,0 +1,134 @@
+package main
+
+// Built-in schemas for the Kinds this tool understands out of the box.
+// These are ordinary Schema values decoded from YAML text, exactly like a
+// schema a user would load from disk - nothing here is special-cased.
+
+var podSchema = MustLoadSchema([]byte(`
+apiVersion: v1
+kind: Pod
+rules:
+  - path: metadata.name
+    required: true
+    notEmpty: true
+  - path: metadata.labels
+    allValuesScalar: true
+  - path: spec
+    required: true
+  - path: spec.os
+    enum: [linux, windows]
+  - path: spec.containers
+    required: true
+  - path: spec.containers.[].name
+    required: true
+    notEmpty: true
+    pattern: '^[a-z0-9]+(_[a-z0-9]+)*$'
+    ruleID: container-name-format
+  - path: spec.containers.[].image
+    required: true
+    pattern: '^registry.bigbrother.io/(.*):(.*)$'
+    ruleID: image-registry-mismatch
+  - path: spec.containers.[].resources
+    required: true
+  - path: spec.containers.[].resources.requests.cpu
+    minInt: 1
+    ruleID: cpu-out-of-range
+  - path: spec.containers.[].resources.limits.cpu
+    minInt: 1
+    ruleID: cpu-out-of-range
+  - path: spec.containers.[].resources.requests.memory
+    pattern: '^(\d+)(Mi|Gi|Ki)$'
+    minQuantity: 1
+    ruleID: memory-quantity-format
+  - path: spec.containers.[].resources.limits.memory
+    pattern: '^(\d+)(Mi|Gi|Ki)$'
+    minQuantity: 1
+    ruleID: memory-quantity-format
+  - path: spec.containers.[].ports.[].containerPort
+    required: true
+    minInt: 0
+    maxInt: 65535
+    ruleID: port-out-of-range
+  - path: spec.containers.[].ports.[].protocol
+    enum: [TCP, UDP]
+    ruleID: protocol-unsupported
+  - path: spec.containers.[].readinessProbe.httpGet
+    required: true
+  - path: spec.containers.[].readinessProbe.httpGet.path
+    required: true
+    pattern: '^/'
+  - path: spec.containers.[].readinessProbe.httpGet.port
+    required: true
+    minInt: 0
+    maxInt: 65535
+    ruleID: port-out-of-range
+  - path: spec.containers.[].livenessProbe.httpGet
+    required: true
+  - path: spec.containers.[].livenessProbe.httpGet.path
+    required: true
+    pattern: '^/'
+  - path: spec.containers.[].livenessProbe.httpGet.port
+    required: true
+    minInt: 0
+    maxInt: 65535
+    ruleID: port-out-of-range
+`))
+
+var deploymentSchema = MustLoadSchema([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+rules:
+  - path: metadata.name
+    required: true
+    notEmpty: true
+  - path: spec
+    required: true
+  - path: spec.selector
+    required: true
+  - path: spec.template
+    required: true
+  - path: spec.replicas
+    minInt: 0
+`))
+
+var serviceSchema = MustLoadSchema([]byte(`
+apiVersion: v1
+kind: Service
+rules:
+  - path: metadata.name
+    required: true
+    notEmpty: true
+  - path: spec
+    required: true
+  - path: spec.type
+    enum: [ClusterIP, NodePort, LoadBalancer, ExternalName]
+  - path: spec.ports
+    required: true
+  - path: spec.ports.[].port
+    required: true
+    minInt: 0
+    maxInt: 65535
+  - path: spec.ports.[].protocol
+    enum: [TCP, UDP]
+`))
+
+var configMapSchema = MustLoadSchema([]byte(`
+apiVersion: v1
+kind: ConfigMap
+rules:
+  - path: metadata.name
+    required: true
+    notEmpty: true
+  - path: data
+    allValuesScalar: true
+`))
+
+var namespaceSchema = MustLoadSchema([]byte(`
+apiVersion: v1
+kind: Namespace
+rules:
+  - path: metadata.name
+    required: true
+    notEmpty: true
+    pattern: '^[a-z0-9-]+$'
+`))
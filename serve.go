@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// admissionReview is the subset of the admission.k8s.io/v1 AdmissionReview
+// contract this server needs: decode a request carrying the object under
+// review, and reply with an allowed/denied response for the same uid.
+type admissionReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Request    *admissionRequest  `json:"request,omitempty"`
+	Response   *admissionResponse `json:"response,omitempty"`
+}
+
+type admissionRequest struct {
+	UID    string          `json:"uid"`
+	Object json.RawMessage `json:"object"`
+}
+
+type admissionResponse struct {
+	UID     string           `json:"uid"`
+	Allowed bool             `json:"allowed"`
+	Status  *admissionStatus `json:"status,omitempty"`
+}
+
+type admissionStatus struct {
+	Message string `json:"message,omitempty"`
+}
+
+// runServe starts the HTTPS AdmissionReview server.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8443", "address to listen on")
+	certFile := fs.String("cert", "", "TLS certificate file")
+	keyFile := fs.String("key", "", "TLS private key file")
+	rulesPath := fs.String("rules", "", "path to a CEL policy file with org-specific rules")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *certFile == "" || *keyFile == "" {
+		return fmt.Errorf("serve requires --cert and --key")
+	}
+
+	if *rulesPath != "" {
+		policy, err := LoadPolicyFile(*rulesPath)
+		if err != nil {
+			return err
+		}
+		policyRules = NewPolicyEngine(policy)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", handleAdmissionReview)
+	mux.HandleFunc("/healthz", handleHealthz)
+
+	server := &http.Server{Addr: *addr, Handler: mux}
+	return server.ListenAndServeTLS(*certFile, *keyFile)
+}
+
+func handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// handleAdmissionReview decodes an AdmissionReview request, runs it through
+// the same validators and policy rules as the CLI, and responds with an
+// AdmissionReview carrying allowed=false plus an aggregated message when any
+// error-severity finding was produced.
+func handleAdmissionReview(w http.ResponseWriter, r *http.Request) {
+	var review admissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("cannot decode AdmissionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "AdmissionReview.request is required", http.StatusBadRequest)
+		return
+	}
+
+	uid := review.Request.UID
+
+	var wrapper yaml.Node
+	if err := yaml.Unmarshal(review.Request.Object, &wrapper); err != nil {
+		respondAdmission(w, uid, false, fmt.Sprintf("cannot parse object: %v", err))
+		return
+	}
+	if len(wrapper.Content) == 0 {
+		respondAdmission(w, uid, false, "object is empty")
+		return
+	}
+
+	result := validateDocument("admission-request", 0, wrapper.Content[0])
+
+	if !hasErrorSeverity([]DocumentResult{result}) {
+		respondAdmission(w, uid, true, "")
+		return
+	}
+	respondAdmission(w, uid, false, aggregateMessages(result.Findings))
+}
+
+func aggregateMessages(findings []Finding) string {
+	messages := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		messages = append(messages, finding.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func respondAdmission(w http.ResponseWriter, uid string, allowed bool, message string) {
+	review := admissionReview{
+		APIVersion: "admission.k8s.io/v1",
+		Kind:       "AdmissionReview",
+		Response: &admissionResponse{
+			UID:     uid,
+			Allowed: allowed,
+		},
+	}
+	if message != "" {
+		review.Response.Status = &admissionStatus{Message: message}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(review)
+}
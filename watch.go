@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const watchDebounce = 100 * time.Millisecond
+
+// watch re-validates args whenever any matching file is created, written,
+// renamed or removed, debouncing bursts of events (e.g. an editor doing a
+// rename-then-write save) into a single re-run.
+func watch(args []string, recursive bool, reporter Reporter) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot start watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := addWatches(watcher, args, recursive); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(watchDebounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	pending := false
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				// Many editors rename-then-write on save; the old watch
+				// died with the inode, so re-add it once the path exists
+				// again. Ignore the error: it may simply not exist yet.
+				_ = watcher.Add(event.Name)
+			}
+			if !isWatchedChange(event) {
+				continue
+			}
+			pending = true
+			timer.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Println("watch error:", err)
+		case <-timer.C:
+			if !pending {
+				continue
+			}
+			pending = false
+			runOnce(args, recursive, reporter)
+		}
+	}
+}
+
+func isWatchedChange(event fsnotify.Event) bool {
+	if !isYAMLFile(event.Name) {
+		return false
+	}
+	return event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) != 0
+}
+
+// addWatches resolves args the same way resolveInputs does, but watches
+// directories (recursing when recursive is set) rather than individual
+// files, so files created later are picked up automatically.
+func addWatches(watcher *fsnotify.Watcher, args []string, recursive bool) error {
+	dirs := make(map[string]bool)
+
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return fmt.Errorf("invalid glob %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			matches = []string{arg}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				continue
+			}
+			if info.IsDir() {
+				collectWatchDirs(match, recursive, dirs)
+				continue
+			}
+			dirs[filepath.Dir(match)] = true
+		}
+	}
+
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("cannot watch %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+func collectWatchDirs(dir string, recursive bool, dirs map[string]bool) {
+	dirs[dir] = true
+	if !recursive {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			collectWatchDirs(filepath.Join(dir, entry.Name()), recursive, dirs)
+		}
+	}
+}
+
+// runOnce re-resolves args so newly created files are included, then
+// validates and reports the result. Errors are logged rather than fatal,
+// since a single save-loop blip shouldn't kill the watch.
+func runOnce(args []string, recursive bool, reporter Reporter) {
+	files, err := resolveInputs(args, recursive)
+	if err != nil {
+		log.Println("error:", err)
+		return
+	}
+
+	results, err := validateManifesto(files)
+	if err != nil {
+		log.Println("error:", err)
+		return
+	}
+
+	if err := reporter.Report(os.Stdout, results); err != nil {
+		log.Println("error:", err)
+	}
+}